@@ -0,0 +1,149 @@
+// Package modbus provides Modbus framing transports built on top of the
+// serial package. It currently implements the Modbus ASCII transfer mode;
+// Modbus RTU is left to the caller, which can frame requests directly on top
+// of a serial.Port using the 3.5-character silence convention.
+package modbus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pauloriordan/serial"
+)
+
+// Transporter sends an application-layer PDU (function code + data) to a
+// Modbus slave and returns the PDU of its response, handling the on-the-wire
+// framing.
+type Transporter interface {
+	// Send sends pdu and returns the response PDU.
+	Send(pdu []byte) (response []byte, err error)
+	// Close releases the underlying serial port.
+	Close() error
+}
+
+// ASCIIClient is a Transporter that frames requests and responses using the
+// Modbus ASCII protocol: ':' + hex(slave ID + PDU) + hex(LRC) + "\r\n".
+type ASCIIClient struct {
+	port    serial.Port
+	slaveID byte
+	reader  *bufio.Reader
+}
+
+// NewASCIIClient opens the serial port described by c and returns a
+// Transporter that frames requests to slaveID using Modbus ASCII. c is
+// copied, not mutated.
+//
+// If c.InterCharacterTimeout is unset, it defaults to the inter-character
+// timeout recommended by the Modbus spec for the configured baud rate: 1s at
+// 19200 baud and above, or ~11 bit times per character below that, and is
+// passed on to serial.Open. Send itself reads each response up to its
+// trailing '\n'; it does not end a frame on inter-character silence.
+// c.Timeout, left as the caller set it (or unbounded, if they didn't), bounds
+// each wait for more response data.
+func NewASCIIClient(c *serial.Config, slaveID byte) (*ASCIIClient, error) {
+	cfg := *c
+	if cfg.InterCharacterTimeout == 0 {
+		cfg.InterCharacterTimeout = frameTimeout(cfg.BaudRate)
+	}
+	port, err := serial.Open(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ASCIIClient{
+		port:    port,
+		slaveID: slaveID,
+		reader:  bufio.NewReader(port),
+	}, nil
+}
+
+// Send implements Transporter.
+func (c *ASCIIClient) Send(pdu []byte) (response []byte, err error) {
+	if _, err = c.port.Write(EncodeASCII(c.slaveID, pdu)); err != nil {
+		return nil, fmt.Errorf("modbus: could not write ASCII frame: %v", err)
+	}
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("modbus: could not read ASCII frame: %v", err)
+	}
+	respSlaveID, response, err := DecodeASCII(line)
+	if err != nil {
+		return nil, err
+	}
+	if respSlaveID != c.slaveID {
+		return nil, fmt.Errorf("modbus: response slave ID %d does not match request %d", respSlaveID, c.slaveID)
+	}
+	return response, nil
+}
+
+// Close closes the underlying serial port.
+func (c *ASCIIClient) Close() error {
+	return c.port.Close()
+}
+
+// EncodeASCII frames pdu addressed to slaveID as a Modbus ASCII request:
+// ':' + hex(slaveID + pdu) + hex(LRC) + "\r\n".
+func EncodeASCII(slaveID byte, pdu []byte) []byte {
+	data := make([]byte, 0, 1+len(pdu))
+	data = append(data, slaveID)
+	data = append(data, pdu...)
+	lrc := lrc(data)
+
+	frame := make([]byte, 0, 1+hex.EncodedLen(len(data)+1)+2)
+	frame = append(frame, ':')
+	frame = appendHex(frame, data)
+	frame = appendHex(frame, []byte{lrc})
+	frame = append(frame, '\r', '\n')
+	return frame
+}
+
+// DecodeASCII parses a Modbus ASCII frame (as produced by EncodeASCII,
+// including the trailing "\r\n") and returns the slave ID and PDU, after
+// verifying the LRC.
+func DecodeASCII(frame []byte) (slaveID byte, pdu []byte, err error) {
+	frame = bytes.TrimRight(frame, "\r\n")
+	if len(frame) < 1 || frame[0] != ':' {
+		return 0, nil, fmt.Errorf("modbus: ASCII frame missing leading ':': %q", frame)
+	}
+	data := make([]byte, hex.DecodedLen(len(frame)-1))
+	if _, err = hex.Decode(data, frame[1:]); err != nil {
+		return 0, nil, fmt.Errorf("modbus: could not hex-decode ASCII frame: %v", err)
+	}
+	if len(data) < 2 {
+		return 0, nil, fmt.Errorf("modbus: ASCII frame too short")
+	}
+	payload, gotLRC := data[:len(data)-1], data[len(data)-1]
+	if want := lrc(payload); want != gotLRC {
+		return 0, nil, fmt.Errorf("modbus: LRC mismatch: got %#x, want %#x", gotLRC, want)
+	}
+	return payload[0], payload[1:], nil
+}
+
+// lrc computes the Modbus LRC: the two's-complement of the 8-bit sum of data.
+func lrc(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return -sum
+}
+
+func appendHex(dst, src []byte) []byte {
+	buf := make([]byte, hex.EncodedLen(len(src)))
+	hex.Encode(buf, src)
+	return append(dst, bytes.ToUpper(buf)...)
+}
+
+// frameTimeout returns the inter-character timeout recommended by the Modbus
+// spec for baudRate: 1s at 19200 baud or above, otherwise ~11 bit times per
+// character.
+func frameTimeout(baudRate int) time.Duration {
+	if baudRate <= 0 || baudRate >= 19200 {
+		return time.Second
+	}
+	msPerChar := math.Ceil(1000 * 11 / float64(baudRate))
+	return time.Duration(msPerChar) * time.Millisecond
+}
@@ -0,0 +1,89 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pauloriordan/serial"
+)
+
+func TestEncodeDecodeASCIIRoundTrip(t *testing.T) {
+	pdu := []byte{0x03, 0x00, 0x6B, 0x00, 0x03}
+	frame := EncodeASCII(0x11, pdu)
+
+	wantPrefix, wantSuffix := byte(':'), "\r\n"
+	if frame[0] != wantPrefix {
+		t.Fatalf("frame does not start with %q: %q", wantPrefix, frame)
+	}
+	if string(frame[len(frame)-2:]) != wantSuffix {
+		t.Fatalf("frame does not end with %q: %q", wantSuffix, frame)
+	}
+
+	slaveID, gotPDU, err := DecodeASCII(frame)
+	if err != nil {
+		t.Fatalf("DecodeASCII: %v", err)
+	}
+	if slaveID != 0x11 {
+		t.Errorf("slaveID = %#x, want 0x11", slaveID)
+	}
+	if !bytes.Equal(gotPDU, pdu) {
+		t.Errorf("pdu = %x, want %x", gotPDU, pdu)
+	}
+}
+
+func TestDecodeASCIIBadLRC(t *testing.T) {
+	frame := EncodeASCII(0x11, []byte{0x03, 0x00})
+	// Flip a bit in the hex-encoded payload ('1' becomes '3', still a valid
+	// hex digit), leaving the trailing LRC alone.
+	frame[1] ^= 0x02
+	if _, _, err := DecodeASCII(frame); err == nil {
+		t.Fatal("DecodeASCII did not detect a corrupted frame")
+	}
+}
+
+func TestDecodeASCIIMissingColon(t *testing.T) {
+	if _, _, err := DecodeASCII([]byte("1103006B0003\r\n")); err == nil {
+		t.Fatal("DecodeASCII did not reject a frame without a leading ':'")
+	}
+}
+
+func TestLRC(t *testing.T) {
+	// Worked example from the Modbus ASCII spec: slave 0x11, function 0x03,
+	// start 0x006B, count 0x0003.
+	data := []byte{0x11, 0x03, 0x00, 0x6B, 0x00, 0x03}
+	if got := lrc(data); got != 0x7E {
+		t.Errorf("lrc(%x) = %#x, want 0x7e", data, got)
+	}
+}
+
+func TestFrameTimeout(t *testing.T) {
+	cases := []struct {
+		baudRate int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{19200, time.Second},
+		{115200, time.Second},
+		{9600, 2 * time.Millisecond},
+		{300, 37 * time.Millisecond},
+	}
+	for _, c := range cases {
+		if got := frameTimeout(c.baudRate); got != c.want {
+			t.Errorf("frameTimeout(%d) = %v, want %v", c.baudRate, got, c.want)
+		}
+	}
+}
+
+func TestNewASCIIClientDoesNotMutateConfig(t *testing.T) {
+	c := &serial.Config{Address: "/dev/does-not-exist", BaudRate: 9600}
+	before := *c
+
+	// The open itself is expected to fail in this environment; what matters
+	// is that c is untouched regardless.
+	NewASCIIClient(c, 0x11)
+
+	if *c != before {
+		t.Fatalf("NewASCIIClient mutated the caller's Config: %+v != %+v", *c, before)
+	}
+}
@@ -0,0 +1,40 @@
+// +build darwin freebsd openbsd netbsd
+
+package serial
+
+import (
+	"context"
+	"time"
+)
+
+// modemPollInterval is how often WaitForModemChange re-reads the modem
+// status on platforms without TIOCMIWAIT (everything but Linux).
+const modemPollInterval = 50 * time.Millisecond
+
+// WaitForModemChange blocks until a line set in mask changes. TIOCMIWAIT is
+// Linux-only, so here we fall back to polling ModemStatus every
+// modemPollInterval.
+func (p *port) WaitForModemChange(ctx context.Context, mask ModemBits) (ModemBits, error) {
+	before, err := p.ModemStatus()
+	if err != nil {
+		return ModemBits{}, err
+	}
+
+	ticker := time.NewTicker(modemPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ModemBits{}, ctx.Err()
+		case <-ticker.C:
+			after, err := p.ModemStatus()
+			if err != nil {
+				return ModemBits{}, err
+			}
+			if modemBitsChanged(before, after, mask) {
+				return after, nil
+			}
+			before = after
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package serial
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Port is the interface for controlling a serial port connection. Use New()
+// to obtain an implementation for the current platform, or Open() as a
+// convenience that also calls Open(c) on it.
+type Port interface {
+	// Open connects to the given serial port and configures it according to c.
+	Open(c *Config) error
+	// Close releases the underlying resources of the port. Any Read or
+	// ReadContext blocked on the port is woken up and returns ErrClosed.
+	Close() error
+
+	// Read reads from the serial port. Port must be opened before calling
+	// this method. It returns as soon as at least one byte is available, or
+	// ErrTimeout if none arrives within Config.Timeout.
+	Read(b []byte) (n int, err error)
+	// ReadContext behaves like Read, but also returns ctx.Err() if ctx is
+	// cancelled before data becomes available.
+	ReadContext(ctx context.Context, b []byte) (n int, err error)
+
+	// Write writes data to the serial port.
+	Write(b []byte) (n int, err error)
+	// WriteContext behaves like Write, but also returns ctx.Err() if ctx is
+	// cancelled before the data can be written.
+	WriteContext(ctx context.Context, b []byte) (n int, err error)
+
+	// FlushInputBuffer discards data received but not read.
+	FlushInputBuffer() error
+	// FlushOutputBuffer discards data written but not transmitted.
+	FlushOutputBuffer() error
+
+	// SetDTR raises or lowers the DTR modem control line.
+	SetDTR(dtr bool) error
+	// SetRTS raises or lowers the RTS modem control line.
+	SetRTS(rts bool) error
+	// ModemStatus reads the current state of the CTS, DSR, RI, DCD, DTR and
+	// RTS modem control lines.
+	ModemStatus() (ModemBits, error)
+	// SendBreak asserts a break condition on the line for the given duration.
+	SendBreak(d time.Duration) error
+	// WaitForModemChange blocks until one of the lines set in mask changes
+	// state, or ctx is done, and returns the new modem line state. Only the
+	// fields set in mask are meaningful to the caller, but the full state is
+	// returned for convenience.
+	WaitForModemChange(ctx context.Context, mask ModemBits) (ModemBits, error)
+
+	// GetBaudRate returns the baud rate applied by the most recent Open, so
+	// callers can verify a non-standard rate was actually accepted.
+	GetBaudRate() (int, error)
+
+	// SetReadDeadline sets the deadline for future Read and ReadContext
+	// calls, as with net.Conn. A zero value removes the deadline.
+	SetReadDeadline(t time.Time) error
+	// SetWriteDeadline sets the deadline for future Write and WriteContext
+	// calls.
+	SetWriteDeadline(t time.Time) error
+	// SetDeadline sets both the read and write deadlines.
+	SetDeadline(t time.Time) error
+}
+
+// ModemBits represents the state of a serial port's modem control lines.
+type ModemBits struct {
+	CTS bool
+	DSR bool
+	RI  bool
+	DCD bool
+	DTR bool
+	RTS bool
+}
+
+// Config contains the low-level configuration for a serial port connection.
+type Config struct {
+	// Address is the serial port device name, e.g. "/dev/ttyUSB0" or "COM1".
+	Address string
+	// BaudRate is the bit rate, e.g. 19200. Defaults to 19200, the value
+	// required by the Modbus spec.
+	BaudRate int
+	// DataBits is the number of data bits. Defaults to 8.
+	DataBits int
+	// StopBits is the number of stop bits: 1 or 2. Defaults to 1.
+	StopBits int
+	// Parity is one of "N" (none), "E" (even, the default) or "O" (odd).
+	Parity string
+	// Rtscts enables RTS/CTS hardware flow control.
+	Rtscts bool
+	// Dsrdtr raises DTR for the lifetime of the connection.
+	Dsrdtr bool
+	// Timeout is how long Read blocks waiting for the first byte of data
+	// before returning ErrTimeout. Zero means wait forever.
+	Timeout time.Duration
+	// InterCharacterTimeout, if non-zero, is written to the termios VTIME
+	// field (with VMIN=1) on POSIX platforms. VTIME only has decisecond
+	// resolution, so values under 100ms are rounded up to 100ms rather than
+	// being silently disabled. With VMIN=1 a read is satisfied by the first
+	// byte, and the port is opened non-blocking (O_NDELAY), so Read still
+	// returns as soon as any data is available; it does not wait for this
+	// much idle time after the first byte. Windows ignores this field.
+	InterCharacterTimeout time.Duration
+	// RS485 holds RS485-specific driver options.
+	RS485 RS485Config
+}
+
+// RS485Config provides RS485 specific configuration for RS485 capable
+// drivers, configured via an ioctl at Open time.
+type RS485Config struct {
+	// Enabled controls whether RS485 support is enabled.
+	Enabled bool
+	// DelayRtsBeforeSend is the delay between asserting RTS and sending data.
+	DelayRtsBeforeSend time.Duration
+	// DelayRtsAfterSend is the delay between sending data and de-asserting RTS.
+	DelayRtsAfterSend time.Duration
+	// RtsHighDuringSend drives RTS high while sending, instead of low.
+	RtsHighDuringSend bool
+	// RtsHighAfterSend drives RTS high after sending, instead of low.
+	RtsHighAfterSend bool
+	// RxDuringTx allows the receiver to stay enabled while transmitting.
+	RxDuringTx bool
+}
+
+// ErrTimeout is returned by Read when no data arrives within Config.Timeout.
+var ErrTimeout = errors.New("serial: timeout")
+
+// ErrClosed is returned by a pending Read, ReadContext, Write or
+// WriteContext when the port is closed while the call is in flight.
+var ErrClosed = errors.New("serial: port closed")
+
+// Open opens and configures the serial port described by c.
+func Open(c *Config) (Port, error) {
+	p := New()
+	if err := p.Open(c); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
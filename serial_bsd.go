@@ -0,0 +1,9 @@
+// +build freebsd openbsd netbsd
+
+package serial
+
+// supportsCustomBaud reports that this platform only supports the
+// POSIX-defined baud rate table; there is no portable way to request an
+// arbitrary rate on these BSDs without platform-specific ioctls we don't
+// implement here.
+const supportsCustomBaud = false
@@ -0,0 +1,123 @@
+// +build freebsd
+
+package serial
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// setCustomBaudRate is unreachable: newTermios already rejects any baud rate
+// outside the POSIX table before Open ever gets here, since
+// supportsCustomBaud (serial_bsd.go) is false on this platform. It exists
+// only so the package links.
+func setCustomBaudRate(fd int, baudRate int) error {
+	return fmt.Errorf("serial: custom baud rates are not supported on this platform")
+}
+
+// On FreeBSD termios, the baud rate lives directly in Termios.Ispeed and
+// Termios.Ospeed as the real integer rate, and there is no CMSPAR
+// equivalent. tcCrtsCts mirrors <sys/termios.h>'s CRTSCTS, which the syscall
+// package doesn't export: CCTS_OFLOW (0x10000) | CRTS_IFLOW (0x20000).
+const (
+	tcCbaud   = 0
+	tcCbaudEx = 0
+	tcCmsPar  = 0
+	tcCrtsCts = 0x30000
+)
+
+// baudRates maps a requested bit rate to the Ispeed/Ospeed value
+// cfSetIspeed/cfSetOspeed apply. On FreeBSD the B* constants equal the
+// literal rate, so the table is this straightforward.
+var baudRates = map[int]uint32{
+	50:     syscall.B50,
+	75:     syscall.B75,
+	110:    syscall.B110,
+	134:    syscall.B134,
+	150:    syscall.B150,
+	200:    syscall.B200,
+	300:    syscall.B300,
+	600:    syscall.B600,
+	1200:   syscall.B1200,
+	1800:   syscall.B1800,
+	2400:   syscall.B2400,
+	4800:   syscall.B4800,
+	9600:   syscall.B9600,
+	19200:  syscall.B19200,
+	38400:  syscall.B38400,
+	57600:  syscall.B57600,
+	115200: syscall.B115200,
+	230400: syscall.B230400,
+}
+
+// charSizes maps a requested data bit width to its Cflag CSIZE value.
+var charSizes = map[int]uint32{
+	5: syscall.CS5,
+	6: syscall.CS6,
+	7: syscall.CS7,
+	8: syscall.CS8,
+}
+
+// cfSetIspeed/cfSetOspeed mirror the termios(3) functions of the same name.
+func cfSetIspeed(t *syscall.Termios, speed uint32) {
+	t.Ispeed = speed
+}
+
+func cfSetOspeed(t *syscall.Termios, speed uint32) {
+	t.Ospeed = speed
+}
+
+// tcgetattr/tcsetattr mirror the termios(3) functions of the same name, via
+// the TIOCGETA/TIOCSETA ioctls.
+func tcgetattr(fd int, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TIOCGETA), uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func tcsetattr(fd int, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TIOCSETA), uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// tcflush mirrors tcflush(3). queue is one of syscall.TCIFLUSH/TCOFLUSH.
+func tcflush(fd int, queue int) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TIOCFLUSH), uintptr(queue))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// fdSet mirrors <sys/select.h>'s fd_set on FreeBSD: FD_SETSIZE (1024) bits
+// packed 32 to a word. We define this ourselves instead of using
+// syscall.FdSet so fdset/fdisset/syscallSelect don't depend on that type's
+// exact field name matching across platforms.
+type fdSet struct {
+	bits [32]uint32
+}
+
+func fdset(fd int, set *fdSet) {
+	set.bits[fd/32] |= 1 << (uint(fd) % 32)
+}
+
+func fdisset(fd int, set *fdSet) bool {
+	return set.bits[fd/32]&(1<<(uint(fd)%32)) != 0
+}
+
+// syscallSelect calls select(2) directly via SYS_SELECT, rather than
+// syscall.Select, since that wrapper's return signature isn't consistent
+// across platforms.
+func syscallSelect(nfd int, r, w, e *fdSet, timeout *syscall.Timeval) error {
+	_, _, errno := syscall.Syscall6(syscall.SYS_SELECT, uintptr(nfd), uintptr(unsafe.Pointer(r)), uintptr(unsafe.Pointer(w)), uintptr(unsafe.Pointer(e)), uintptr(unsafe.Pointer(timeout)), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// +build freebsd
+
+package serial
+
+import "testing"
+
+func TestFdsetFdisset(t *testing.T) {
+	var set fdSet
+	fdset(3, &set)
+	fdset(40, &set)
+
+	if !fdisset(3, &set) {
+		t.Error("fd 3 was set but fdisset reports it unset")
+	}
+	if !fdisset(40, &set) {
+		t.Error("fd 40 was set but fdisset reports it unset")
+	}
+	if fdisset(4, &set) {
+		t.Error("fd 4 was never set but fdisset reports it set")
+	}
+}
@@ -0,0 +1,176 @@
+// +build linux
+
+package serial
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// supportsCustomBaud reports that this platform can apply baud rates outside
+// the POSIX-defined table, via BOTHER + termios2.
+const supportsCustomBaud = true
+
+const (
+	// bother is BOTHER from asm-generic/termbits.h: request c_ispeed/c_ospeed
+	// be used verbatim instead of one of the standard B* constants.
+	bother = 0o010000
+	// tcsets2/tcgets2 are TCSETS2/TCGETS2 from asm-generic/ioctls.h, the
+	// termios2-aware counterparts of TCSETS/TCGETS.
+	tcsets2 = 0x402c542b
+	tcgets2 = 0x802c542a
+	// tcflsh is TCFLSH from asm-generic/ioctls.h. The syscall package only
+	// exports this under that name on arm64/mips/ppc64/s390x/loong64/riscv64;
+	// amd64/386/arm don't have it, so it's defined as a raw constant here
+	// like tcsets2/tcgets2 above instead.
+	tcflsh = 0x540B
+)
+
+// tcCbaud, tcCbaudEx, tcCmsPar and tcCrtsCts are CBAUD, CBAUDEX, CMSPAR and
+// CRTSCTS from asm-generic/termbits.h. The standard syscall package doesn't
+// export them (they aren't in POSIX), so newTermios in serial_posix.go
+// relies on this file (and its darwin/bsd counterparts) to supply them.
+const (
+	tcCbaud   = 0010017
+	tcCbaudEx = 0010000
+	tcCmsPar  = 0x40000000
+	tcCrtsCts = 0x80000000
+)
+
+// baudRates maps a requested bit rate to the Cflag value newTermios ORs in,
+// and the raw value cfSetIspeed/cfSetOspeed write into Termios.Ispeed/Ospeed
+// (on Linux these happen to be the same B* constant).
+var baudRates = map[int]uint32{
+	50:      syscall.B50,
+	75:      syscall.B75,
+	110:     syscall.B110,
+	134:     syscall.B134,
+	150:     syscall.B150,
+	200:     syscall.B200,
+	300:     syscall.B300,
+	600:     syscall.B600,
+	1200:    syscall.B1200,
+	1800:    syscall.B1800,
+	2400:    syscall.B2400,
+	4800:    syscall.B4800,
+	9600:    syscall.B9600,
+	19200:   syscall.B19200,
+	38400:   syscall.B38400,
+	57600:   syscall.B57600,
+	115200:  syscall.B115200,
+	230400:  syscall.B230400,
+	460800:  syscall.B460800,
+	500000:  syscall.B500000,
+	576000:  syscall.B576000,
+	921600:  syscall.B921600,
+	1000000: syscall.B1000000,
+	1152000: syscall.B1152000,
+	1500000: syscall.B1500000,
+	2000000: syscall.B2000000,
+	2500000: syscall.B2500000,
+	3000000: syscall.B3000000,
+	3500000: syscall.B3500000,
+	4000000: syscall.B4000000,
+}
+
+// charSizes maps a requested data bit width to its Cflag CSIZE value.
+var charSizes = map[int]uint32{
+	5: syscall.CS5,
+	6: syscall.CS6,
+	7: syscall.CS7,
+	8: syscall.CS8,
+}
+
+// cfSetIspeed/cfSetOspeed mirror the termios(3) functions of the same name.
+// On Linux the input/output speed fields simply echo the B* Cflag constant.
+func cfSetIspeed(t *syscall.Termios, speed uint32) {
+	t.Ispeed = speed
+}
+
+func cfSetOspeed(t *syscall.Termios, speed uint32) {
+	t.Ospeed = speed
+}
+
+// tcgetattr/tcsetattr mirror the termios(3) functions of the same name, via
+// the TCGETS/TCSETS ioctls.
+func tcgetattr(fd int, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TCGETS), uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func tcsetattr(fd int, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(syscall.TCSETS), uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// tcflush mirrors tcflush(3) via the TCFLSH ioctl; queue is one of
+// syscall.TCIFLUSH/TCOFLUSH/TCIOFLUSH.
+func tcflush(fd int, queue int) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(tcflsh), uintptr(queue))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// fdSet is the select(2) fd set type serial_posix.go builds against; on
+// Linux it's simply syscall.FdSet.
+type fdSet = syscall.FdSet
+
+// fdset/fdisset set/test a bit in a syscall.FdSet for select(2). Linux's
+// FdSet.Bits is an array of 64-bit words regardless of architecture.
+func fdset(fd int, set *syscall.FdSet) {
+	set.Bits[fd/64] |= 1 << (uint(fd) % 64)
+}
+
+func fdisset(fd int, set *syscall.FdSet) bool {
+	return set.Bits[fd/64]&(1<<(uint(fd)%64)) != 0
+}
+
+// syscallSelect wraps syscall.Select so callers don't need to unpack the
+// (n, err) result.
+func syscallSelect(nfd int, r, w, e *syscall.FdSet, timeout *syscall.Timeval) error {
+	_, err := syscall.Select(nfd, r, w, e, timeout)
+	return err
+}
+
+// termios2 mirrors struct termios2 from asm-generic/termbits.h: a regular
+// termios with c_ispeed/c_ospeed appended as plain integers, used instead of
+// syscall.Termios whenever BOTHER is in Cflag.
+type termios2 struct {
+	Iflag  uint32
+	Oflag  uint32
+	Cflag  uint32
+	Lflag  uint32
+	Line   byte
+	Cc     [19]byte
+	Ispeed uint32
+	Ospeed uint32
+}
+
+// setCustomBaudRate applies a baud rate outside the POSIX-defined table by
+// switching the line to BOTHER and writing the raw rate into
+// c_ispeed/c_ospeed via TCSETS2.
+func setCustomBaudRate(fd int, baudRate int) error {
+	var t2 termios2
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(tcgets2), uintptr(unsafe.Pointer(&t2))); errno != 0 {
+		return fmt.Errorf("serial: TCGETS2 failed: %v", errno)
+	}
+
+	t2.Cflag &^= uint32(tcCbaud | tcCbaudEx)
+	t2.Cflag |= uint32(bother)
+	t2.Ispeed = uint32(baudRate)
+	t2.Ospeed = uint32(baudRate)
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(tcsets2), uintptr(unsafe.Pointer(&t2))); errno != 0 {
+		return fmt.Errorf("serial: TCSETS2 failed: %v", errno)
+	}
+	return nil
+}
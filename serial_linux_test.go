@@ -0,0 +1,24 @@
+// +build linux
+
+package serial
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestFdsetFdisset(t *testing.T) {
+	var set syscall.FdSet
+	fdset(3, &set)
+	fdset(70, &set)
+
+	if !fdisset(3, &set) {
+		t.Error("fd 3 was set but fdisset reports it unset")
+	}
+	if !fdisset(70, &set) {
+		t.Error("fd 70 was set but fdisset reports it unset")
+	}
+	if fdisset(4, &set) {
+		t.Error("fd 4 was never set but fdisset reports it set")
+	}
+}
@@ -3,6 +3,7 @@
 package serial
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -18,6 +19,52 @@ type port struct {
 	oldTermios *syscall.Termios
 
 	timeout time.Duration
+
+	// readDeadline/writeDeadline are the net.Conn-style absolute deadlines
+	// set via SetReadDeadline/SetWriteDeadline/SetDeadline. A zero Time
+	// means no deadline.
+	readDeadline  time.Time
+	writeDeadline time.Time
+
+	// baudRate is the rate applied by the most recent successful Open; it is
+	// reported back by GetBaudRate.
+	baudRate int
+
+	// cancelR/cancelW are the ends of a self-pipe added to the Select fd set
+	// so that Close and context cancellation can wake a blocked Read/Write.
+	cancelR int
+	cancelW int
+}
+
+// GetBaudRate returns the baud rate applied by the most recent Open, so
+// callers can verify a non-standard rate was actually accepted by the
+// driver.
+func (p *port) GetBaudRate() (int, error) {
+	if p.fd == -1 {
+		return 0, ErrClosed
+	}
+	return p.baudRate, nil
+}
+
+// SetReadDeadline sets the deadline for future Read and ReadContext calls, as
+// with net.Conn. A zero value removes the deadline, leaving p.timeout (from
+// Config.Timeout) as the only idle cutoff.
+func (p *port) SetReadDeadline(t time.Time) error {
+	p.readDeadline = t
+	return nil
+}
+
+// SetWriteDeadline sets the deadline for future Write and WriteContext calls.
+func (p *port) SetWriteDeadline(t time.Time) error {
+	p.writeDeadline = t
+	return nil
+}
+
+// SetDeadline sets both the read and write deadlines.
+func (p *port) SetDeadline(t time.Time) error {
+	p.readDeadline = t
+	p.writeDeadline = t
+	return nil
 }
 
 const (
@@ -38,7 +85,7 @@ type rs485_ioctl_opts struct {
 
 // New allocates and returns a new serial port controller.
 func New() Port {
-	return &port{fd: -1}
+	return &port{fd: -1, cancelR: -1, cancelW: -1}
 }
 
 // Open connects to the given serial port.
@@ -50,6 +97,11 @@ func (p *port) Open(c *Config) (err error) {
 	if err != nil {
 		return
 	}
+	if p.cancelR, p.cancelW, err = newCancelPipe(); err != nil {
+		syscall.Close(p.fd)
+		p.fd = -1
+		return
+	}
 	// Backup current termios to restore on closing.
 	p.backupTermios()
 
@@ -67,6 +119,19 @@ func (p *port) Open(c *Config) (err error) {
 		p.oldTermios = nil
 		return err
 	}
+	if _, ok := baudRates[c.BaudRate]; c.BaudRate != 0 && !ok {
+		// newTermios already checked that the platform supports this; apply
+		// it now that the rest of the termios settings are in place.
+		if err = setCustomBaudRate(p.fd, c.BaudRate); err != nil {
+			p.Close()
+			return err
+		}
+		p.baudRate = c.BaudRate
+	} else if c.BaudRate != 0 {
+		p.baudRate = c.BaudRate
+	} else {
+		p.baudRate = 19200
+	}
 	if err = enableRS485(p.fd, &c.RS485); err != nil {
 		p.Close()
 		return err
@@ -87,25 +152,77 @@ func (p *port) Close() (err error) {
 	err = syscall.Close(p.fd)
 	p.fd = -1
 	p.oldTermios = nil
+	// Wake any Read/ReadContext blocked in Select, then tear down the pipe.
+	p.wakeCancel()
+	syscall.Close(p.cancelR)
+	syscall.Close(p.cancelW)
+	p.cancelR = -1
+	p.cancelW = -1
 	return
 }
 
 // Read reads from serial port. Port must be opened before calling this method.
-// It is blocked until all data received or timeout after p.timeout.
+// It returns as soon as at least one byte is available, or ErrTimeout if none
+// arrives within p.timeout.
 func (p *port) Read(b []byte) (n int, err error) {
-	var rfds syscall.FdSet
+	return p.read(context.Background(), b)
+}
+
+// ReadContext behaves like Read, but also returns ctx.Err() as soon as ctx is
+// cancelled, instead of waiting out the rest of p.timeout.
+func (p *port) ReadContext(ctx context.Context, b []byte) (n int, err error) {
+	return p.read(ctx, b)
+}
 
+func (p *port) read(ctx context.Context, b []byte) (n int, err error) {
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
 	fd := p.fd
+	if fd == -1 {
+		return 0, ErrClosed
+	}
+	cancelFd := p.cancelR
+
+	// Wake the Select below as soon as ctx is done, without leaking this
+	// goroutine: it also exits once read() returns on its own.
+	done := make(chan struct{})
+	defer close(done)
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				p.wakeCancel()
+			case <-done:
+			}
+		}()
+	}
+
+	var rfds fdSet
 	fdset(fd, &rfds)
+	fdset(cancelFd, &rfds)
+	maxFd := fd
+	if cancelFd > maxFd {
+		maxFd = cancelFd
+	}
 
+	wait := p.timeout
+	if !p.readDeadline.IsZero() {
+		if remaining := time.Until(p.readDeadline); wait <= 0 || remaining < wait {
+			wait = remaining
+		}
+		if wait <= 0 {
+			return 0, ErrTimeout
+		}
+	}
 	var tv *syscall.Timeval
-	if p.timeout > 0 {
-		timeout := syscall.NsecToTimeval(p.timeout.Nanoseconds())
+	if wait > 0 {
+		timeout := syscall.NsecToTimeval(wait.Nanoseconds())
 		tv = &timeout
 	}
 	for {
 		// If syscall.Select() returns EINTR (Interrupted system call), retry it
-		if err = syscallSelect(fd+1, &rfds, nil, nil, tv); err == nil {
+		if err = syscallSelect(maxFd+1, &rfds, nil, nil, tv); err == nil {
 			break
 		}
 		if err != syscall.EINTR {
@@ -113,6 +230,13 @@ func (p *port) Read(b []byte) (n int, err error) {
 			return
 		}
 	}
+	if fdisset(cancelFd, &rfds) {
+		p.drainCancel()
+		if err = ctx.Err(); err != nil {
+			return 0, err
+		}
+		return 0, ErrClosed
+	}
 	if !fdisset(fd, &rfds) {
 		// Timeout
 		err = ErrTimeout
@@ -128,12 +252,64 @@ func (p *port) Read(b []byte) (n int, err error) {
 	return
 }
 
-// Write writes data to the serial port.
+// Write writes data to the serial port, honoring any deadline set by
+// SetWriteDeadline/SetDeadline.
 func (p *port) Write(b []byte) (n int, err error) {
+	return p.WriteContext(context.Background(), b)
+}
+
+// WriteContext behaves like Write, but returns ctx.Err() instead of writing
+// if ctx is already cancelled, or ErrTimeout if the write deadline has
+// already passed.
+func (p *port) WriteContext(ctx context.Context, b []byte) (n int, err error) {
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+	if !p.writeDeadline.IsZero() && !time.Now().Before(p.writeDeadline) {
+		return 0, ErrTimeout
+	}
 	n, err = syscall.Write(p.fd, b)
 	return
 }
 
+// newCancelPipe creates a non-blocking, close-on-exec pipe used to wake a
+// goroutine parked in Select on the read end.
+func newCancelPipe() (r, w int, err error) {
+	var fds [2]int
+	if err = syscall.Pipe(fds[:]); err != nil {
+		return -1, -1, err
+	}
+	syscall.CloseOnExec(fds[0])
+	syscall.CloseOnExec(fds[1])
+	if err = syscall.SetNonblock(fds[0], true); err != nil {
+		return -1, -1, err
+	}
+	if err = syscall.SetNonblock(fds[1], true); err != nil {
+		return -1, -1, err
+	}
+	return fds[0], fds[1], nil
+}
+
+// wakeCancel wakes anyone blocked in Select on p.cancelR. It is safe to call
+// more than once; once the pipe is non-empty, further writes just return
+// EAGAIN which we ignore.
+func (p *port) wakeCancel() {
+	if p.cancelW == -1 {
+		return
+	}
+	syscall.Write(p.cancelW, []byte{0})
+}
+
+// drainCancel empties the cancel pipe after Select reports it readable.
+func (p *port) drainCancel() {
+	var buf [8]byte
+	for {
+		if _, err := syscall.Read(p.cancelR, buf[:]); err != nil {
+			return
+		}
+	}
+}
+
 func (p *port) setTermios(termios *syscall.Termios) (err error) {
 	if err = tcsetattr(p.fd, termios); err != nil {
 		err = fmt.Errorf("serial: could not set setting: %v", err)
@@ -142,76 +318,107 @@ func (p *port) setTermios(termios *syscall.Termios) (err error) {
 }
 
 func (p *port) setRtsDtr(rts bool, dtr bool) {
-	var status int
+	p.SetRTS(rts)
+	p.SetDTR(dtr)
+}
 
-	syscall.Syscall(
+// getModemBits reads the current modem control line status via TIOCMGET.
+func (p *port) getModemBits() (status int, err error) {
+	_, _, errno := syscall.Syscall(
 		syscall.SYS_IOCTL,
 		uintptr(p.fd),
 		uintptr(syscall.TIOCMGET),
 		uintptr(unsafe.Pointer(&status)))
-
-	if rts {
-		status |= syscall.TIOCM_RTS
-	} else {
-		status &^= syscall.TIOCM_RTS
+	if errno != 0 {
+		return 0, os.NewSyscallError("SYS_IOCTL (TIOCMGET)", errno)
 	}
+	return status, nil
+}
 
-	if dtr {
-		status |= syscall.TIOCM_DTR
+// setModemBit sets or clears bit in the modem control line status via
+// TIOCMGET followed by TIOCMSET.
+func (p *port) setModemBit(bit int, set bool) error {
+	status, err := p.getModemBits()
+	if err != nil {
+		return err
+	}
+	if set {
+		status |= bit
 	} else {
-		status &^= syscall.TIOCM_DTR
+		status &^= bit
 	}
-
-	syscall.Syscall(
+	_, _, errno := syscall.Syscall(
 		syscall.SYS_IOCTL,
 		uintptr(p.fd),
 		uintptr(syscall.TIOCMSET),
 		uintptr(unsafe.Pointer(&status)))
+	if errno != 0 {
+		return os.NewSyscallError("SYS_IOCTL (TIOCMSET)", errno)
+	}
+	return nil
 }
 
-func (p *port) setDtr(dtr bool) {
-	var status int
+// SetDTR raises or lowers the DTR modem control line.
+func (p *port) SetDTR(dtr bool) error {
+	return p.setModemBit(syscall.TIOCM_DTR, dtr)
+}
 
-	syscall.Syscall(
-		syscall.SYS_IOCTL,
-		uintptr(p.fd),
-		uintptr(syscall.TIOCMGET),
-		uintptr(unsafe.Pointer(&status)))
+// SetRTS raises or lowers the RTS modem control line.
+func (p *port) SetRTS(rts bool) error {
+	return p.setModemBit(syscall.TIOCM_RTS, rts)
+}
 
-	if dtr {
-		status |= syscall.TIOCM_DTR
-	} else {
-		status &^= syscall.TIOCM_DTR
+// ModemStatus reads the current state of the CTS, DSR, RI, DCD, DTR and RTS
+// modem control lines via TIOCMGET.
+func (p *port) ModemStatus() (ModemBits, error) {
+	status, err := p.getModemBits()
+	if err != nil {
+		return ModemBits{}, err
 	}
-
-	syscall.Syscall(
-		syscall.SYS_IOCTL,
-		uintptr(p.fd),
-		uintptr(syscall.TIOCMSET),
-		uintptr(unsafe.Pointer(&status)))
+	return modemBitsFromStatus(status), nil
 }
 
-func (p *port) setRts(rts bool) {
-	var status int
+func modemBitsFromStatus(status int) ModemBits {
+	return ModemBits{
+		CTS: status&syscall.TIOCM_CTS != 0,
+		DSR: status&syscall.TIOCM_DSR != 0,
+		RI:  status&syscall.TIOCM_RI != 0,
+		DCD: status&syscall.TIOCM_CD != 0,
+		DTR: status&syscall.TIOCM_DTR != 0,
+		RTS: status&syscall.TIOCM_RTS != 0,
+	}
+}
 
-	// Get the modem bits status
-	syscall.Syscall(
-		syscall.SYS_IOCTL,
-		uintptr(p.fd),
-		uintptr(syscall.TIOCMGET),
-		uintptr(unsafe.Pointer(&status)))
+// modemBitsChanged reports whether any line set in mask differs between a
+// and b. It is used by WaitForModemChange implementations to decide whether
+// a wakeup is relevant to the caller.
+func modemBitsChanged(a, b, mask ModemBits) bool {
+	return (mask.CTS && a.CTS != b.CTS) ||
+		(mask.DSR && a.DSR != b.DSR) ||
+		(mask.RI && a.RI != b.RI) ||
+		(mask.DCD && a.DCD != b.DCD) ||
+		(mask.DTR && a.DTR != b.DTR) ||
+		(mask.RTS && a.RTS != b.RTS)
+}
 
-	if rts {
-		status |= syscall.TIOCM_RTS
-	} else {
-		status &^= syscall.TIOCM_RTS
+// SendBreak asserts a break condition on the line for the given duration.
+func (p *port) SendBreak(d time.Duration) error {
+	if err := ioctlNoArg(p.fd, syscall.TIOCSBRK); err != nil {
+		return fmt.Errorf("serial: could not set break: %v", err)
+	}
+	time.Sleep(d)
+	if err := ioctlNoArg(p.fd, syscall.TIOCCBRK); err != nil {
+		return fmt.Errorf("serial: could not clear break: %v", err)
 	}
+	return nil
+}
 
-	// Update according to the conf.Rtscts setting
-	syscall.Syscall(syscall.SYS_IOCTL,
-		uintptr(p.fd),
-		uintptr(syscall.TIOCMSET),
-		uintptr(unsafe.Pointer(&status)))
+func ioctlNoArg(fd int, req uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), req, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
 }
 
 // backupTermios saves current termios setting.
@@ -256,8 +463,14 @@ func newTermios(c *Config, originalTermios *syscall.Termios) (termios *syscall.T
 		var ok bool
 		flag, ok = baudRates[c.BaudRate]
 		if !ok {
-			err = fmt.Errorf("serial: unsupported baud rate %v", c.BaudRate)
-			return
+			if !supportsCustomBaud {
+				err = fmt.Errorf("serial: unsupported baud rate %v", c.BaudRate)
+				return
+			}
+			// Not a POSIX-defined rate (e.g. 31250 for MIDI, 250000 for
+			// DMX). Leave a standard placeholder here; setCustomBaudRate
+			// applies the real rate once tcsetattr has taken effect.
+			flag = syscall.B9600
 		}
 	}
 
@@ -332,10 +545,31 @@ func newTermios(c *Config, originalTermios *syscall.Termios) (termios *syscall.T
 		syscall.IGNBRK | syscall.PARMRK | syscall.IXON | syscall.IXOFF |
 		syscall.IXANY)
 
-	// Set both MIN and TIME to zero. Read always returns immediately with as many
-	// characters as are available in the queue
-	termios.Cc[syscall.VMIN] = 0
-	termios.Cc[syscall.VTIME] = 0
+	if c.InterCharacterTimeout > 0 {
+		// Set VMIN=1 and VTIME=N deciseconds. With VMIN=1 a read is
+		// satisfied by the first byte, and p.fd is opened O_NDELAY, so read()
+		// below still returns whatever a single syscall.Read yields; it does
+		// not wait for N deciseconds of silence.
+		// VTIME's resolution is whole deciseconds, so anything under 100ms
+		// (e.g. the few milliseconds Modbus ASCII wants at high baud rates)
+		// is rounded up to the 100ms floor rather than truncated to 0.
+		deciseconds := int(c.InterCharacterTimeout / (100 * time.Millisecond))
+		if c.InterCharacterTimeout%(100*time.Millisecond) != 0 {
+			deciseconds++
+		}
+		if deciseconds < 1 {
+			deciseconds = 1
+		} else if deciseconds > 255 {
+			deciseconds = 255
+		}
+		termios.Cc[syscall.VMIN] = 1
+		termios.Cc[syscall.VTIME] = uint8(deciseconds)
+	} else {
+		// Read always returns immediately with as many characters as are
+		// already available in the queue.
+		termios.Cc[syscall.VMIN] = 0
+		termios.Cc[syscall.VTIME] = 0
+	}
 
 	// Enable / disable rtscts flow control
 	if c.Rtscts {
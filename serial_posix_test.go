@@ -0,0 +1,23 @@
+// +build darwin linux freebsd openbsd netbsd
+
+package serial
+
+import "testing"
+
+func TestModemBitsChanged(t *testing.T) {
+	before := ModemBits{CTS: false, DSR: true, DCD: false}
+	after := ModemBits{CTS: true, DSR: true, DCD: false}
+
+	if !modemBitsChanged(before, after, ModemBits{CTS: true}) {
+		t.Error("expected a CTS change to be reported when CTS is masked in")
+	}
+	if modemBitsChanged(before, after, ModemBits{DSR: true}) {
+		t.Error("DSR didn't change, but masking it in reported a change anyway")
+	}
+	if modemBitsChanged(before, after, ModemBits{}) {
+		t.Error("an empty mask should never report a change")
+	}
+	if modemBitsChanged(before, before, ModemBits{CTS: true, DSR: true, RI: true, DCD: true, DTR: true, RTS: true}) {
+		t.Error("comparing identical ModemBits should never report a change")
+	}
+}
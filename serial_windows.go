@@ -0,0 +1,510 @@
+// +build windows
+
+package serial
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// port implements Port interface for Windows, using overlapped I/O so that
+// ReadContext/WriteContext can cancel an in-flight operation with CancelIoEx.
+type port struct {
+	handle syscall.Handle
+
+	timeout time.Duration
+
+	// rOverlapped/wOverlapped are reused across calls; mu serialises access
+	// since CancelIoEx must not race a fresh ReadFile/WriteFile on the same
+	// OVERLAPPED.
+	mu          sync.Mutex
+	rOverlapped syscall.Overlapped
+	wOverlapped syscall.Overlapped
+
+	// baudRate is the rate applied by the most recent successful Open.
+	baudRate int
+
+	// readDeadline/writeDeadline are the net.Conn-style absolute deadlines
+	// set via SetReadDeadline/SetWriteDeadline/SetDeadline. A zero Time
+	// means no deadline.
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+// SetReadDeadline sets the deadline for future Read and ReadContext calls.
+func (p *port) SetReadDeadline(t time.Time) error {
+	p.readDeadline = t
+	return nil
+}
+
+// SetWriteDeadline sets the deadline for future Write and WriteContext calls.
+func (p *port) SetWriteDeadline(t time.Time) error {
+	p.writeDeadline = t
+	return nil
+}
+
+// SetDeadline sets both the read and write deadlines.
+func (p *port) SetDeadline(t time.Time) error {
+	p.readDeadline = t
+	p.writeDeadline = t
+	return nil
+}
+
+// GetBaudRate returns the baud rate applied by the most recent Open.
+func (p *port) GetBaudRate() (int, error) {
+	if p.handle == syscall.InvalidHandle {
+		return 0, ErrClosed
+	}
+	return p.baudRate, nil
+}
+
+// New allocates and returns a new serial port controller.
+func New() Port {
+	return &port{handle: syscall.InvalidHandle}
+}
+
+// Open connects to the given serial port.
+func (p *port) Open(c *Config) (err error) {
+	path, err := syscall.UTF16PtrFromString("\\\\.\\" + c.Address)
+	if err != nil {
+		return err
+	}
+	h, err := syscall.CreateFile(
+		path,
+		syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		0,
+		nil,
+		syscall.OPEN_EXISTING,
+		syscall.FILE_FLAG_OVERLAPPED,
+		0)
+	if err != nil {
+		return fmt.Errorf("serial: could not open %s: %v", c.Address, err)
+	}
+	p.handle = h
+
+	if err = p.setCommState(c); err != nil {
+		syscall.CloseHandle(p.handle)
+		p.handle = syscall.InvalidHandle
+		return err
+	}
+	if c.RS485.Enabled {
+		// RS485 auto-direction control is not exposed by the Windows COM
+		// driver model; callers needing it must toggle RTS themselves.
+		syscall.CloseHandle(p.handle)
+		p.handle = syscall.InvalidHandle
+		return fmt.Errorf("serial: RS485 is not supported on windows")
+	}
+	p.timeout = c.Timeout
+	if c.Dsrdtr {
+		if err = p.SetDTR(true); err != nil {
+			p.Close()
+			return err
+		}
+	}
+
+	p.rOverlapped.HEvent, err = createEvent()
+	if err != nil {
+		p.Close()
+		return err
+	}
+	p.wOverlapped.HEvent, err = createEvent()
+	if err != nil {
+		p.Close()
+		return err
+	}
+	return nil
+}
+
+func (p *port) Close() (err error) {
+	if p.handle == syscall.InvalidHandle {
+		return nil
+	}
+	// Cancel any Read/ReadContext or Write/WriteContext still parked in
+	// waitOverlapped before tearing down the handle and event out from under
+	// them; passing a nil Overlapped cancels every pending op on the handle,
+	// not just one.
+	cancelIoEx(p.handle, nil)
+	err = syscall.CloseHandle(p.handle)
+	p.handle = syscall.InvalidHandle
+	if p.rOverlapped.HEvent != 0 {
+		syscall.CloseHandle(p.rOverlapped.HEvent)
+	}
+	if p.wOverlapped.HEvent != 0 {
+		syscall.CloseHandle(p.wOverlapped.HEvent)
+	}
+	return err
+}
+
+// Read reads from the serial port. It returns as soon as at least one byte
+// is available, or ErrTimeout if none arrives within p.timeout.
+func (p *port) Read(b []byte) (int, error) {
+	return p.ReadContext(context.Background(), b)
+}
+
+// ReadContext behaves like Read, but cancels the pending ReadFile via
+// CancelIoEx as soon as ctx is done, returning ctx.Err().
+func (p *port) ReadContext(ctx context.Context, b []byte) (n int, err error) {
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+	if !p.readDeadline.IsZero() && !time.Now().Before(p.readDeadline) {
+		return 0, ErrTimeout
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err = syscall.ReadFile(p.handle, b, nil, &p.rOverlapped); err != nil && err != syscall.ERROR_IO_PENDING {
+		return 0, fmt.Errorf("serial: ReadFile failed: %v", err)
+	}
+	return p.waitOverlapped(ctx, &p.rOverlapped, deadlineTimeout(p.timeout, p.readDeadline))
+}
+
+// deadlineTimeout returns whichever of timeout (relative, <=0 meaning none)
+// or deadline (absolute, zero meaning none) elapses first, as a relative
+// duration; 0 means no timeout at all.
+func deadlineTimeout(timeout time.Duration, deadline time.Time) time.Duration {
+	if deadline.IsZero() {
+		return timeout
+	}
+	remaining := time.Until(deadline)
+	if timeout <= 0 || remaining < timeout {
+		return remaining
+	}
+	return timeout
+}
+
+// Write writes data to the serial port.
+func (p *port) Write(b []byte) (int, error) {
+	return p.WriteContext(context.Background(), b)
+}
+
+// WriteContext behaves like Write, but cancels the pending WriteFile via
+// CancelIoEx as soon as ctx is done, returning ctx.Err().
+func (p *port) WriteContext(ctx context.Context, b []byte) (n int, err error) {
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+	if !p.writeDeadline.IsZero() && !time.Now().Before(p.writeDeadline) {
+		return 0, ErrTimeout
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err = syscall.WriteFile(p.handle, b, nil, &p.wOverlapped); err != nil && err != syscall.ERROR_IO_PENDING {
+		return 0, fmt.Errorf("serial: WriteFile failed: %v", err)
+	}
+	return p.waitOverlapped(ctx, &p.wOverlapped, deadlineTimeout(0, p.writeDeadline))
+}
+
+// waitOverlapped waits for ov to complete, for ctx to be cancelled, or for
+// timeout to elapse (0 means wait forever), cancelling the I/O via
+// CancelIoEx whenever it gives up early.
+func (p *port) waitOverlapped(ctx context.Context, ov *syscall.Overlapped, timeout time.Duration) (n int, err error) {
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		var transferred uint32
+		e := getOverlappedResult(p.handle, ov, &transferred, true)
+		n = int(transferred)
+		done <- e
+	}()
+
+	select {
+	case err = <-done:
+		if err == syscall.ERROR_OPERATION_ABORTED {
+			// Only Close cancels without going through the cases below.
+			err = ErrClosed
+		}
+	case <-ctx.Done():
+		cancelIoEx(p.handle, ov)
+		<-done
+		err = ctx.Err()
+	case <-timeoutCh:
+		cancelIoEx(p.handle, ov)
+		<-done
+		err = ErrTimeout
+	}
+	return
+}
+
+// createEvent creates a manual-reset event for use as an OVERLAPPED.HEvent,
+// via the CreateEventW kernel32 entry point (not exposed by package syscall).
+func createEvent() (syscall.Handle, error) {
+	r, _, err := procCreateEventW.Call(0, 1, 0, 0)
+	if r == 0 {
+		return syscall.InvalidHandle, fmt.Errorf("serial: CreateEventW failed: %v", err)
+	}
+	return syscall.Handle(r), nil
+}
+
+// getOverlappedResult waits for (or reports the result of) an overlapped
+// I/O operation, via the kernel32 entry point of the same name (not exposed
+// by package syscall).
+func getOverlappedResult(handle syscall.Handle, ov *syscall.Overlapped, transferred *uint32, wait bool) error {
+	waitArg := uintptr(0)
+	if wait {
+		waitArg = 1
+	}
+	r, _, err := procGetOverlappedResult.Call(
+		uintptr(handle),
+		uintptr(unsafe.Pointer(ov)),
+		uintptr(unsafe.Pointer(transferred)),
+		waitArg)
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+var (
+	kernel32                = syscall.NewLazyDLL("kernel32.dll")
+	procCancelIoEx          = kernel32.NewProc("CancelIoEx")
+	procCreateEventW        = kernel32.NewProc("CreateEventW")
+	procGetOverlappedResult = kernel32.NewProc("GetOverlappedResult")
+	procPurgeComm           = kernel32.NewProc("PurgeComm")
+	procGetCommState        = kernel32.NewProc("GetCommState")
+	procSetCommState        = kernel32.NewProc("SetCommState")
+	procSetCommTimeouts     = kernel32.NewProc("SetCommTimeouts")
+)
+
+// cancelIoEx cancels the overlapped I/O operation ov on handle.
+func cancelIoEx(handle syscall.Handle, ov *syscall.Overlapped) {
+	procCancelIoEx.Call(uintptr(handle), uintptr(unsafe.Pointer(ov)))
+}
+
+// dcb mirrors the Win32 DCB struct (see MSDN), only the fields this package
+// sets are named individually; BitFields packs fBinary..fDummy2.
+type dcb struct {
+	DCBlength  uint32
+	BaudRate   uint32
+	BitFields  uint32
+	wReserved  uint16
+	XonLim     uint16
+	XoffLim    uint16
+	ByteSize   byte
+	Parity     byte
+	StopBits   byte
+	XonChar    byte
+	XoffChar   byte
+	ErrorChar  byte
+	EofChar    byte
+	EvtChar    byte
+	wReserved1 uint16
+}
+
+const (
+	dcbFBinary   = 1 << 0
+	dcbFParity   = 1 << 1
+	dcbFRtsCtl   = 2 << 12 // fRtsControl = RTS_CONTROL_HANDSHAKE
+	dcbFOutxCts  = 1 << 2
+)
+
+// setCommState configures the COM port's DCB (baud/data/parity/stop bits)
+// and timeouts from c.
+func (p *port) setCommState(c *Config) error {
+	var d dcb
+	d.DCBlength = uint32(unsafe.Sizeof(d))
+	if r, _, _ := procGetCommState.Call(uintptr(p.handle), uintptr(unsafe.Pointer(&d))); r == 0 {
+		return fmt.Errorf("serial: GetCommState failed")
+	}
+
+	baud := c.BaudRate
+	if baud == 0 {
+		baud = 19200
+	}
+	d.BaudRate = uint32(baud)
+	p.baudRate = baud
+
+	d.ByteSize = byte(c.DataBits)
+	if d.ByteSize == 0 {
+		d.ByteSize = 8
+	}
+
+	switch c.StopBits {
+	case 0, 1:
+		d.StopBits = 0 // ONESTOPBIT
+	case 2:
+		d.StopBits = 2 // TWOSTOPBITS
+	default:
+		return fmt.Errorf("serial: unsupported stop bits %v", c.StopBits)
+	}
+
+	d.BitFields |= dcbFBinary
+	switch c.Parity {
+	case "N":
+		d.Parity = 0 // NOPARITY
+		d.BitFields &^= dcbFParity
+	case "", "E":
+		d.Parity = 2 // EVENPARITY
+		d.BitFields |= dcbFParity
+	case "O":
+		d.Parity = 1 // ODDPARITY
+		d.BitFields |= dcbFParity
+	default:
+		return fmt.Errorf("serial: unsupported parity %v", c.Parity)
+	}
+
+	if c.Rtscts {
+		d.BitFields |= dcbFRtsCtl | dcbFOutxCts
+	}
+
+	if r, _, _ := procSetCommState.Call(uintptr(p.handle), uintptr(unsafe.Pointer(&d))); r == 0 {
+		return fmt.Errorf("serial: SetCommState failed")
+	}
+
+	// MAXDWORD read interval and multiplier with a constant just under
+	// MAXDWORD makes ReadFile return immediately with whatever is already in
+	// the input buffer, or else complete as soon as one byte arrives; the
+	// Config.Timeout/deadline cutoff is instead implemented by waitOverlapped.
+	timeouts := struct {
+		ReadIntervalTimeout         uint32
+		ReadTotalTimeoutMultiplier  uint32
+		ReadTotalTimeoutConstant    uint32
+		WriteTotalTimeoutMultiplier uint32
+		WriteTotalTimeoutConstant   uint32
+	}{
+		ReadIntervalTimeout:        0xFFFFFFFF,
+		ReadTotalTimeoutMultiplier: 0xFFFFFFFF,
+		ReadTotalTimeoutConstant:   0xFFFFFFFE,
+	}
+	if r, _, _ := procSetCommTimeouts.Call(uintptr(p.handle), uintptr(unsafe.Pointer(&timeouts))); r == 0 {
+		return fmt.Errorf("serial: SetCommTimeouts failed")
+	}
+	return nil
+}
+
+// SetDTR raises or lowers the DTR modem control line.
+func (p *port) SetDTR(dtr bool) error {
+	return escapeCommFunction(p.handle, dtr, setDTR, clrDTR)
+}
+
+// SetRTS raises or lowers the RTS modem control line.
+func (p *port) SetRTS(rts bool) error {
+	return escapeCommFunction(p.handle, rts, setRTS, clrRTS)
+}
+
+func escapeCommFunction(h syscall.Handle, set bool, onFunc, offFunc uintptr) error {
+	fn := offFunc
+	if set {
+		fn = onFunc
+	}
+	if r, _, _ := procEscapeCommFunction.Call(uintptr(h), fn); r == 0 {
+		return fmt.Errorf("serial: EscapeCommFunction failed")
+	}
+	return nil
+}
+
+// ModemStatus reads the current state of the CTS, DSR, RI and DCD modem
+// control lines via GetCommModemStatus. GetCommModemStatus does not report
+// DTR or RTS, so those fields are always false here.
+func (p *port) ModemStatus() (ModemBits, error) {
+	var status uint32
+	if r, _, _ := procGetCommModemStatus.Call(uintptr(p.handle), uintptr(unsafe.Pointer(&status))); r == 0 {
+		return ModemBits{}, fmt.Errorf("serial: GetCommModemStatus failed")
+	}
+	return ModemBits{
+		CTS: status&msCTSOn != 0,
+		DSR: status&msDSROn != 0,
+		RI:  status&msRingOn != 0,
+		DCD: status&msRLSDOn != 0,
+	}, nil
+}
+
+// SendBreak asserts a break condition on the line for the given duration.
+func (p *port) SendBreak(d time.Duration) error {
+	if r, _, _ := procSetCommBreak.Call(uintptr(p.handle)); r == 0 {
+		return fmt.Errorf("serial: SetCommBreak failed")
+	}
+	time.Sleep(d)
+	if r, _, _ := procClearCommBreak.Call(uintptr(p.handle)); r == 0 {
+		return fmt.Errorf("serial: ClearCommBreak failed")
+	}
+	return nil
+}
+
+// WaitForModemChange polls ModemStatus every modemPollInterval; Windows'
+// native WaitCommEvent is edge-triggered per handle and would conflict with
+// the overlapped reads/writes already using p.rOverlapped/p.wOverlapped.
+func (p *port) WaitForModemChange(ctx context.Context, mask ModemBits) (ModemBits, error) {
+	before, err := p.ModemStatus()
+	if err != nil {
+		return ModemBits{}, err
+	}
+	ticker := time.NewTicker(modemPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ModemBits{}, ctx.Err()
+		case <-ticker.C:
+			after, err := p.ModemStatus()
+			if err != nil {
+				return ModemBits{}, err
+			}
+			if modemBitsChanged(before, after, mask) {
+				return after, nil
+			}
+			before = after
+		}
+	}
+}
+
+const modemPollInterval = 50 * time.Millisecond
+
+func modemBitsChanged(a, b, mask ModemBits) bool {
+	return (mask.CTS && a.CTS != b.CTS) ||
+		(mask.DSR && a.DSR != b.DSR) ||
+		(mask.RI && a.RI != b.RI) ||
+		(mask.DCD && a.DCD != b.DCD)
+}
+
+const (
+	setRTS = 3 // SETRTS
+	clrRTS = 4 // CLRRTS
+	setDTR = 5 // SETDTR
+	clrDTR = 6 // CLRDTR
+
+	msCTSOn  = 0x0010
+	msDSROn  = 0x0020
+	msRingOn = 0x0040
+	msRLSDOn = 0x0080
+)
+
+var (
+	procEscapeCommFunction = kernel32.NewProc("EscapeCommFunction")
+	procGetCommModemStatus = kernel32.NewProc("GetCommModemStatus")
+	procSetCommBreak       = kernel32.NewProc("SetCommBreak")
+	procClearCommBreak     = kernel32.NewProc("ClearCommBreak")
+)
+
+func (p *port) FlushInputBuffer() error {
+	return purgeComm(p.handle, purgeRxClear)
+}
+
+func (p *port) FlushOutputBuffer() error {
+	return purgeComm(p.handle, purgeTxClear)
+}
+
+// purgeComm discards buffered data via the PurgeComm kernel32 entry point
+// (not exposed by package syscall).
+func purgeComm(handle syscall.Handle, flags uintptr) error {
+	if r, _, err := procPurgeComm.Call(uintptr(handle), flags); r == 0 {
+		return fmt.Errorf("serial: PurgeComm failed: %v", err)
+	}
+	return nil
+}
+
+const (
+	purgeRxClear = 0x0008
+	purgeTxClear = 0x0004
+)
@@ -0,0 +1,28 @@
+// +build windows
+
+package serial
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadlineTimeout(t *testing.T) {
+	now := time.Now()
+
+	if got := deadlineTimeout(5*time.Second, time.Time{}); got != 5*time.Second {
+		t.Errorf("no deadline: got %v, want the 5s timeout unchanged", got)
+	}
+	if got := deadlineTimeout(0, time.Time{}); got != 0 {
+		t.Errorf("neither timeout nor deadline set: got %v, want 0", got)
+	}
+	if got := deadlineTimeout(0, now.Add(time.Second)); got <= 0 || got > time.Second {
+		t.Errorf("no timeout, deadline in 1s: got %v, want (0, 1s]", got)
+	}
+	if got := deadlineTimeout(time.Hour, now.Add(time.Second)); got <= 0 || got > time.Second {
+		t.Errorf("deadline sooner than timeout: got %v, want (0, 1s]", got)
+	}
+	if got := deadlineTimeout(time.Millisecond, now.Add(time.Hour)); got != time.Millisecond {
+		t.Errorf("timeout sooner than deadline: got %v, want 1ms unchanged", got)
+	}
+}